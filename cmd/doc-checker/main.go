@@ -15,6 +15,8 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
 )
 
 type resourceDefinition struct {
@@ -41,8 +43,18 @@ var (
 	docTypeDatasource docType = 0
 	docTypeResource   docType = 1
 	debug             *bool
+	watch             *bool
+	format            *string
 )
 
+func describeRule(ruleID string) string {
+	switch ruleID {
+	case "missing-doc-attribute":
+		return "every schema attribute must be documented as `name` in the resource/datasource's website markdown"
+	}
+	return ""
+}
+
 func Debugf(format string, a ...interface{}) {
 	if *debug {
 		log.Printf(format, a...)
@@ -150,6 +162,8 @@ func main() {
 	var providerName = flag.String("provider-name", "", "prefix name of the provider")
 	var providerPath = flag.String("provider-path", "", "path to the terraform provider to check")
 	debug = flag.Bool("debug", false, "enable debug output")
+	watch = flag.Bool("watch", false, "watch provider-path and website/ for changes and re-check incrementally")
+	format = flag.String("format", "text", "diagnostic output format: text, json, sarif, checkstyle, github-actions")
 	flag.Parse()
 
 	if providerPath == nil || *providerPath == "" || providerName == nil || *providerName == "" {
@@ -157,6 +171,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	reporter, err := diagnostic.ReporterFor(*format, describeRule)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	log.Printf("checking documentation for %q", *providerPath)
 	prov, err := parseProviderDefinition(fmt.Sprintf("%s/provider.go", *providerPath))
 	if err != nil {
@@ -177,25 +196,51 @@ func main() {
 		Debugf("docs of %q: %d\n", k, len(v))
 	}
 
-	filepath.Walk(*providerPath, func(path string, info os.FileInfo, err error) error {
+	if *watch {
+		runner := NewRunner(*providerName, prov, docs, reporter)
+		walkGoFiles(*providerPath, runner.Run)
+		websitePath := path.Join(*providerPath, "..", "website")
+		log.Printf("watching %q and %q for changes", *providerPath, websitePath)
+		if err := runner.Watch(*providerPath, websitePath); err != nil {
+			log.Fatalf("watch failed: %v", err)
+		}
+		return
+	}
+
+	var diags []diagnostic.Diagnostic
+	walkGoFiles(*providerPath, func(path string) {
+		diags = append(diags, verifyAttributes(path, prov, docs)...)
+	})
+	if err := reporter.Report(os.Stdout, diags); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func walkGoFiles(root string, fn func(path string)) {
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if strings.HasSuffix(path, "_test.go") {
 			return nil
 		}
 		if !strings.HasSuffix(path, ".go") {
 			return nil
 		}
-		verifyAttributes(path, prov, docs)
+		fn(path)
 		return nil
 	})
 }
 
-func verifyAttributes(path string, prov provider, docs documentation) {
+func verifyAttributes(path string, prov provider, docs documentation) []diagnostic.Diagnostic {
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 	if err != nil {
 		log.Printf("Failed to parse %s: %s\n", path, err)
-		return
+		return nil
 	}
+	return verifyAttributesFile(fset, f, path, prov, docs)
+}
+
+func verifyAttributesFile(fset *token.FileSet, f *ast.File, path string, prov provider, docs documentation) []diagnostic.Diagnostic {
+	var diags []diagnostic.Diagnostic
 
 	// TODO identify type
 	for _, decl := range f.Decls {
@@ -293,7 +338,17 @@ func verifyAttributes(path string, prov provider, docs documentation) {
 					expectedMarkup = fmt.Sprintf("`%s`", decodeString(lit.Value))
 				}
 				if !bytes.Contains(docset, []byte(expectedMarkup)) {
-					log.Printf("Missing %q in docs of %q\n", expectedMarkup, schemaName)
+					pos := fset.Position(eltt.Pos())
+					diags = append(diags, diagnostic.Diagnostic{
+						File:          path,
+						Line:          pos.Line,
+						Column:        pos.Column,
+						RuleID:        "missing-doc-attribute",
+						Severity:      diagnostic.Error,
+						Message:       fmt.Sprintf("missing %s in docs of %q", expectedMarkup, schemaName),
+						ResourceName:  schemaName,
+						AttributePath: decodeString(name),
+					})
 				}
 				_ = name
 
@@ -306,6 +361,7 @@ func verifyAttributes(path string, prov provider, docs documentation) {
 			}
 		}
 	}
+	return diags
 }
 
 func decodeString(val string) string {
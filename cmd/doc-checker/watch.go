@@ -0,0 +1,178 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
+)
+
+// debounceWindow coalesces editor save bursts (many providers write a
+// file, then immediately gofmt it) into a single re-check.
+const debounceWindow = 200 * time.Millisecond
+
+type fileCache struct {
+	file *ast.File
+}
+
+// Runner turns the one-shot verifyAttributes loop into a long-lived
+// process: Run(path) re-parses and re-checks a single file, Invalidate
+// drops its cached AST so the next Run starts clean.
+type Runner struct {
+	mu           sync.Mutex
+	cache        map[string]*fileCache
+	providerName string
+	prov         provider
+	docs         documentation
+	reporter     diagnostic.Reporter
+}
+
+func NewRunner(providerName string, prov provider, docs documentation, reporter diagnostic.Reporter) *Runner {
+	return &Runner{cache: map[string]*fileCache{}, providerName: providerName, prov: prov, docs: docs, reporter: reporter}
+}
+
+func (r *Runner) Run(path string) {
+	if isDocFile(path) {
+		r.reloadDoc(path)
+		r.mu.Lock()
+		cached := make([]string, 0, len(r.cache))
+		for p := range r.cache {
+			cached = append(cached, p)
+		}
+		r.mu.Unlock()
+		for _, p := range cached {
+			r.Run(p)
+		}
+		return
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		log.Printf("Failed to parse %s: %s\n", path, err)
+		return
+	}
+
+	r.mu.Lock()
+	r.cache[path] = &fileCache{file: f}
+	r.mu.Unlock()
+
+	diags := verifyAttributesFile(fset, f, path, r.prov, r.docs)
+	if err := r.reporter.Report(os.Stdout, diags); err != nil {
+		log.Printf("reporting diagnostics: %v", err)
+	}
+}
+
+func isDocFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".md" || ext == ".markdown"
+}
+
+// reloadDoc re-classifies a single changed markdown file and updates
+// the matching entry in r.docs, mirroring what loadDocumentation does
+// for the full tree at startup.
+func (r *Runner) reloadDoc(path string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read %s: %s\n", path, err)
+		return
+	}
+
+	docName, docType, err := classifyDoc(r.providerName, path, content)
+	if err != nil {
+		Debugf("Ignoring %q due to %v", path, err)
+		return
+	}
+
+	r.mu.Lock()
+	if docType == docTypeDatasource {
+		r.docs.Datasources[docName] = content
+	} else {
+		r.docs.Resources[docName] = content
+	}
+	r.mu.Unlock()
+}
+
+func (r *Runner) Invalidate(path string) {
+	r.mu.Lock()
+	delete(r.cache, path)
+	r.mu.Unlock()
+}
+
+// Watch observes every root (and its subdirectories) for writes to .go
+// or markdown files and re-runs Invalidate+Run for the changed file
+// after debounceWindow has passed without another event for it.
+func (r *Runner) Watch(roots ...string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for _, root := range roots {
+		if err := addRecursive(watcher, root); err != nil {
+			return err
+		}
+	}
+
+	var mu sync.Mutex
+	timers := map[string]*time.Timer{}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if !isRelevant(event.Name) {
+				continue
+			}
+
+			path := event.Name
+			mu.Lock()
+			if t, ok := timers[path]; ok {
+				t.Stop()
+			}
+			timers[path] = time.AfterFunc(debounceWindow, func() {
+				r.Invalidate(path)
+				r.Run(path)
+			})
+			mu.Unlock()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("watch error: %v", err)
+		}
+	}
+}
+
+func isRelevant(path string) bool {
+	for _, ext := range []string{".go", ".md", ".markdown"} {
+		if filepath.Ext(path) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func addRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// AttributeDoc describes a single schema.Schema entry extracted from the
+// provider source, in the shape the website markdown template needs.
+type AttributeDoc struct {
+	Name        string
+	Description string
+	Type        string
+	Required    bool
+	Optional    bool
+	Computed    bool
+	ForceNew    bool
+	Sensitive   bool
+	Deprecated  string
+	Default     string
+	Nested      []AttributeDoc
+}
+
+// ResourceDoc is the intermediate model built from the AST for one
+// resource or data source, independent of how it's eventually rendered.
+type ResourceDoc struct {
+	ProviderName string
+	Name         string
+	Description  string
+	IsDatasource bool
+	SidebarSlug  string
+	Attributes   []AttributeDoc
+}
+
+var (
+	debug        *bool
+	providerName *string
+	providerPath *string
+	check        *bool
+	write        *bool
+	diff         *bool
+)
+
+func Debugf(format string, a ...interface{}) {
+	if *debug {
+		log.Printf(format, a...)
+	}
+}
+
+func init() {
+	providerName = flag.String("provider-name", "", "prefix name of the provider")
+	providerPath = flag.String("provider-path", "", "path to the terraform provider to generate docs for")
+	debug = flag.Bool("debug", false, "enable debug output")
+	check = flag.Bool("check", false, "exit non-zero if generated docs would differ from what's on disk")
+	write = flag.Bool("write", false, "write generated docs to website/docs")
+	diff = flag.Bool("diff", false, "print a diff of generated docs against what's on disk")
+	flag.Parse()
+
+	if providerPath == nil || *providerPath == "" || providerName == nil || *providerName == "" {
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+}
+
+func main() {
+	prov, err := parseProviderDefinition(fmt.Sprintf("%s/provider.go", *providerPath))
+	if err != nil {
+		log.Fatalf("Failed to parse the provider: %q", err)
+	}
+
+	docs := []ResourceDoc{}
+	filepath.Walk(*providerPath, func(p string, info os.FileInfo, err error) error {
+		if strings.HasSuffix(p, "_test.go") || !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+		found, err := resourceDocs(p, prov)
+		if err != nil {
+			log.Printf("Failed to parse %s: %s\n", p, err)
+			return nil
+		}
+		docs = append(docs, found...)
+		return nil
+	})
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+
+	drifted := false
+	for _, d := range docs {
+		rendered, err := render(d)
+		if err != nil {
+			log.Fatalf("Failed to render %q: %s", d.Name, err)
+		}
+
+		dest := destination(*providerPath, d)
+		existing, _ := ioutil.ReadFile(dest)
+
+		if bytes.Equal(existing, rendered) {
+			continue
+		}
+		drifted = true
+
+		switch {
+		case *write:
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				log.Fatalf("Failed to create %q: %s", filepath.Dir(dest), err)
+			}
+			if err := ioutil.WriteFile(dest, rendered, 0644); err != nil {
+				log.Fatalf("Failed to write %q: %s", dest, err)
+			}
+			Debugf("wrote %s\n", dest)
+		case *diff:
+			fmt.Printf("--- %s\n", dest)
+			for _, line := range diffLines(string(existing), string(rendered)) {
+				fmt.Println(line)
+			}
+		default:
+			log.Printf("%s is out of date", dest)
+		}
+	}
+
+	if *check && drifted {
+		os.Exit(1)
+	}
+}
+
+func destination(providerPath string, d ResourceDoc) string {
+	dir := "r"
+	if d.IsDatasource {
+		dir = "d"
+	}
+	name := strings.TrimPrefix(d.Name, d.ProviderName+"_")
+	return path.Join(providerPath, "..", "website", "docs", dir, name+".html.markdown")
+}
+
+// resourceDocs walks a single Go file looking for functions returning
+// *schema.Resource that are registered in the provider's
+// ResourcesMap/DataSourcesMap, and extracts a ResourceDoc for each.
+func resourceDocs(file string, prov provider) ([]ResourceDoc, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []ResourceDoc{}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+			continue
+		}
+		ret, ok := fn.Type.Results.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := ret.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Resource" || sel.X.(*ast.Ident).Name != "schema" {
+			continue
+		}
+
+		name, isDatasource, found := lookup(prov, fn.Name.Name)
+		if !found {
+			Debugf("Could not find matching datasource or resource for %v\n", fn.Name.Name)
+			continue
+		}
+
+		retStmt, ok := fn.Body.List[0].(*ast.ReturnStmt)
+		if !ok {
+			Debugf("TODO structure of %v does not allow parsing yet\n", fn.Name.Name)
+			continue
+		}
+		resourceLit := retStmt.Results[0].(*ast.UnaryExpr).X.(*ast.CompositeLit)
+
+		attrs := []AttributeDoc{}
+		for _, elt := range resourceLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok || kv.Key.(*ast.Ident).Name != "Schema" {
+				continue
+			}
+			schemaLit, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			attrs = append(attrs, attributesOf(schemaLit)...)
+		}
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name < attrs[j].Name })
+
+		docs = append(docs, ResourceDoc{
+			ProviderName: *providerName,
+			Name:         name,
+			Description:  summarize(*providerName, name, isDatasource),
+			IsDatasource: isDatasource,
+			SidebarSlug:  sidebarSlug(*providerName, name, isDatasource),
+			Attributes:   attrs,
+		})
+	}
+	return docs, nil
+}
+
+func lookup(prov provider, fncName string) (name string, isDatasource bool, found bool) {
+	for _, v := range prov.datasources {
+		if v.fnc == fncName {
+			return v.name, true, true
+		}
+	}
+	for _, v := range prov.resources {
+		if v.fnc == fncName {
+			return v.name, false, true
+		}
+	}
+	return "", false, false
+}
+
+func sidebarSlug(providerName, name string, isDatasource bool) string {
+	short := strings.TrimPrefix(name, providerName+"_")
+	if isDatasource {
+		return fmt.Sprintf("docs-%s-datasource-%s", providerName, short)
+	}
+	return fmt.Sprintf("docs-%s-resource-%s", providerName, short)
+}
+
+// summarize produces the one-line frontmatter description shown in the
+// sidebar search results, e.g. "Widget resource." for example_widget.
+func summarize(providerName, name string, isDatasource bool) string {
+	kind := "resource"
+	if isDatasource {
+		kind = "data source"
+	}
+	short := strings.ReplaceAll(strings.TrimPrefix(name, providerName+"_"), "_", " ")
+	return fmt.Sprintf("%s %s.", strings.Title(short), kind)
+}
+
+// attributesOf extracts AttributeDoc entries from a schema.Schema{...}
+// composite literal, recursing into nested Elem: &schema.Resource{...}
+// blocks so the emitted markdown documents sub-attributes too.
+func attributesOf(schemaLit *ast.CompositeLit) []AttributeDoc {
+	attrs := []AttributeDoc{}
+	for _, elt := range schemaLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		def, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		a := AttributeDoc{Name: decodeKey(kv.Key)}
+		for _, fieldElt := range def.Elts {
+			field, ok := fieldElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			fieldName := field.Key.(*ast.Ident).Name
+			switch fieldName {
+			case "Description":
+				a.Description = decodeString(field.Value)
+			case "Type":
+				a.Type = field.Value.(*ast.SelectorExpr).Sel.Name
+			case "Required":
+				a.Required = decodeBool(field.Value)
+			case "Optional":
+				a.Optional = decodeBool(field.Value)
+			case "Computed":
+				a.Computed = decodeBool(field.Value)
+			case "ForceNew":
+				a.ForceNew = decodeBool(field.Value)
+			case "Sensitive":
+				a.Sensitive = decodeBool(field.Value)
+			case "Deprecated":
+				a.Deprecated = decodeString(field.Value)
+			case "Default":
+				a.Default = decodeLit(field.Value)
+			case "Elem":
+				if resourceLit, ok := elemResource(field.Value); ok {
+					for _, resElt := range resourceLit.Elts {
+						resKV, ok := resElt.(*ast.KeyValueExpr)
+						if !ok || resKV.Key.(*ast.Ident).Name != "Schema" {
+							continue
+						}
+						nestedLit, ok := resKV.Value.(*ast.CompositeLit)
+						if !ok {
+							continue
+						}
+						a.Nested = attributesOf(nestedLit)
+					}
+				}
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+func elemResource(expr ast.Expr) (*ast.CompositeLit, bool) {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok {
+		return nil, false
+	}
+	lit, ok := unary.X.(*ast.CompositeLit)
+	return lit, ok
+}
+
+func decodeKey(expr ast.Expr) string {
+	switch k := expr.(type) {
+	case *ast.BasicLit:
+		return decodeString(k)
+	case *ast.Ident:
+		// TODO support constants defined elsewhere…
+		return k.Name
+	}
+	return ""
+}
+
+func decodeString(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	v := lit.Value
+	return v[1 : len(v)-1]
+}
+
+func decodeBool(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+func decodeLit(expr ast.Expr) string {
+	if lit, ok := expr.(*ast.BasicLit); ok {
+		return lit.Value
+	}
+	return ""
+}
+
+var docTemplate = template.Must(template.New("doc").Parse(`---
+layout: "{{.ProviderName}}"
+page_title: "{{.ProviderName}}: {{.Name}}"
+sidebar_current: "{{.SidebarSlug}}"
+description: |-
+  {{.Description}}
+---
+
+# {{if .IsDatasource}}Data Source: {{else}}Resource: {{end}}{{.Name}}
+
+## Example Usage
+
+` + "```hcl\n```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+{{range .Attributes}}
+* ` + "`{{.Name}}`" + ` - ({{if .Required}}Required{{else if .Computed}}Computed{{else}}Optional{{end}}{{if .ForceNew}}, Forces new resource{{end}}{{if .Sensitive}}, Sensitive{{end}}) {{.Description}}{{if .Type}} Type: ` + "`{{.Type}}`" + `.{{end}}{{if .Default}} Defaults to ` + "`{{.Default}}`" + `.{{end}}{{if .Deprecated}} **Deprecated**: {{.Deprecated}}{{end}}
+{{range .Nested}}  * ` + "`{{.Name}}`" + ` - {{if .Required}}(Required){{else if .Computed}}(Computed){{else}}(Optional){{end}} {{.Description}}
+{{end}}{{end}}
+`))
+
+func render(d ResourceDoc) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := docTemplate.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// diffLines compares two texts line by line at equal positions (it does
+// not align inserted or removed lines), good enough for eyeballing what
+// drifted without pulling in a diff library.
+func diffLines(old, new string) []string {
+	oldLines := strings.Split(old, "\n")
+	newLines := strings.Split(new, "\n")
+
+	out := []string{}
+	max := len(oldLines)
+	if len(newLines) > max {
+		max = len(newLines)
+	}
+	for i := 0; i < max; i++ {
+		var o, n string
+		if i < len(oldLines) {
+			o = oldLines[i]
+		}
+		if i < len(newLines) {
+			n = newLines[i]
+		}
+		if o == n {
+			continue
+		}
+		if i < len(oldLines) {
+			out = append(out, "-"+o)
+		}
+		if i < len(newLines) {
+			out = append(out, "+"+n)
+		}
+	}
+	return out
+}
+
+type resourceDefinition struct {
+	name string
+	fnc  string
+}
+
+type provider struct {
+	datasources []resourceDefinition
+	resources   []resourceDefinition
+}
+
+// parseProviderDefinition takes a provider.go file and tries to extract the declared
+// datasources and resources from the AST
+func parseProviderDefinition(path string) (provider, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return provider{}, err
+	}
+	p := provider{}
+	for _, decl := range f.Decls {
+		v, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if v.Name.String() != "Provider" {
+			continue
+		}
+
+		for _, stmt := range v.Body.List {
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok {
+				continue
+			}
+			st := ret.Results[0].(*ast.UnaryExpr).X.(*ast.CompositeLit)
+
+			for _, elt := range st.Elts {
+				elttKey := elt.(*ast.KeyValueExpr).Key.(*ast.Ident)
+				switch {
+				case elttKey.Name == "ResourcesMap":
+					elttValue := elt.(*ast.KeyValueExpr).Value.(*ast.CompositeLit)
+					for _, elttt := range elttValue.Elts {
+						eltttt := elttt.(*ast.KeyValueExpr)
+						p.resources = append(p.resources, resourceDefinition{
+							name: decodeString(eltttt.Key),
+							fnc:  eltttt.Value.(*ast.CallExpr).Fun.(*ast.Ident).Name,
+						})
+					}
+				case elttKey.Name == "DataSourcesMap":
+					elttValue := elt.(*ast.KeyValueExpr).Value.(*ast.CompositeLit)
+					for _, elttt := range elttValue.Elts {
+						eltttt := elttt.(*ast.KeyValueExpr)
+						p.datasources = append(p.datasources, resourceDefinition{
+							name: decodeString(eltttt.Key),
+							fnc:  eltttt.Value.(*ast.CallExpr).Fun.(*ast.Ident).Name,
+						})
+					}
+				default:
+					Debugf("ignoring provider keys %#v\n", elttKey.Name)
+				}
+			}
+		}
+	}
+	return p, nil
+}
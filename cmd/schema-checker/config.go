@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
+)
+
+// RuleConfig is the per-rule section of .tfschemalint.{yaml,hcl}. Enabled
+// defaults to true when the rule isn't mentioned at all.
+type RuleConfig struct {
+	Enabled  *bool    `mapstructure:"enabled"`
+	Severity string   `mapstructure:"severity"`
+	Ignore   []string `mapstructure:"ignore"`
+}
+
+type Config struct {
+	Rules map[string]RuleConfig `mapstructure:"rules"`
+}
+
+// loadConfig reads .tfschemalint.yaml (or .hcl/.json/...), viper-style:
+// any supported format, missing file just means "run every rule at its
+// default severity". path may be either a directory to search for
+// .tfschemalint.* in, or a path to the config file itself.
+func loadConfig(path string) (*Config, error) {
+	v := viper.New()
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName(".tfschemalint")
+		v.AddConfigPath(path)
+	}
+
+	cfg := &Config{}
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return cfg, nil
+}
+
+// enabledRules resolves the registry against the config, returning the
+// rules that should run and, for each, the severity to report it at.
+func (c *Config) enabledRules() map[string]diagnostic.Severity {
+	severities := map[string]diagnostic.Severity{}
+	for id, rule := range registry {
+		rc, ok := c.Rules[id]
+		if !ok {
+			severities[id] = rule.Severity()
+			continue
+		}
+		if rc.Enabled != nil && !*rc.Enabled {
+			continue
+		}
+		if sev, ok := diagnostic.ParseSeverity(rc.Severity); ok && rc.Severity != "" {
+			severities[id] = sev
+		} else {
+			severities[id] = rule.Severity()
+		}
+	}
+	return severities
+}
+
+// ignored reports whether resourceName.path, or resourceName on its own,
+// matches one of the rule's configured ignore globs.
+func (c *Config) ignored(ruleID, resourceName, path string) bool {
+	rc, ok := c.Rules[ruleID]
+	if !ok {
+		return false
+	}
+	candidate := resourceName + "." + path
+	for _, glob := range rc.Ignore {
+		if ok, _ := filepath.Match(glob, candidate); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(glob, resourceName); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -6,203 +6,316 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
+
+	"golang.org/x/tools/go/loader"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
 )
 
 var (
 	debug        *bool
 	providerPath *string
+	format       *string
+	configPath   *string
+	watch        *bool
 )
 
-type schemaCheck func(string) schemaWalker
-
 type schemaWalker func(ast.Node) ast.Visitor
 
 func (fn schemaWalker) Visit(node ast.Node) ast.Visitor {
 	return fn(node)
 }
 
-func debugWalker() schemaWalker {
-	return schemaWalker(func(n ast.Node) ast.Visitor {
-		fmt.Printf("%#v\n", n)
-		return debugWalker()
-	})
+func collectAttributeNames(pkg *loader.PackageInfo, schema ast.Node) []string {
+	names := []string{}
+	ast.Walk(attributeCollector(pkg, &names), schema)
+	return names
 }
 
-type checkFn func(attributeName string, def *ast.CompositeLit, schema ast.Node) error
-
-func checkFnFunc(fn func(attributeName string, def *ast.CompositeLit, schema ast.Node) error) checkFn {
-	return checkFn(fn)
-}
+func attributeCollector(pkg *loader.PackageInfo, res *[]string) schemaWalker {
+	return func(node ast.Node) ast.Visitor {
+		if node == nil {
+			return nil
+		}
 
-func checkDescription(attributeName string, def *ast.CompositeLit, schema ast.Node) error {
-	hasDescription := false
-	for _, elt := range def.Elts {
-		name := elt.(*ast.KeyValueExpr).Key.(*ast.Ident).Name
-		hasDescription = hasDescription || name == "Description"
-	}
-	if hasDescription {
+		k, ok := node.(*ast.KeyValueExpr)
+		if !ok {
+			return attributeCollector(pkg, res)
+		}
+		name, ok := identName(pkg, k.Key)
+		if !ok {
+			return nil
+		}
+		*res = append(*res, name)
 		return nil
 	}
-	return fmt.Errorf("%s: Missing Description attribute", attributeName)
-
 }
 
-func collectAttributeNames(schema ast.Node) []string {
-	names := []string{}
-	ast.Walk(attributeCollector(&names), schema)
-	return names
+// resolver resolves cross-package/cross-file references that the plain
+// AST can't: an *ast.Ident key pointing at a const/var declaration, and
+// an Elem value that is a call to a factory function (possibly imported
+// from another package) returning *schema.Resource. It is backed by a
+// type-checked *loader.Program so SelectorExpr/Ident lookups can cross
+// file and package boundaries.
+type resolver struct {
+	prog *loader.Program
+	// resolved records which factory functions have already been
+	// followed for the current top-level resource, so self-referential
+	// helpers (e.g. a resource embedding itself for a recursive block)
+	// don't recurse forever. A factory already in the set is not followed
+	// again, so a second Elem using the same helper within one resource
+	// is not walked.
+	resolved map[types.Object]bool
+	// severities and cfg gate which rules run and at what level, and
+	// which resource/attribute globs to skip. attributeChecker reads both.
+	severities map[string]diagnostic.Severity
+	cfg        *Config
+	diags      []diagnostic.Diagnostic
+
+	// files and pkgOf are fixed at load time: the ordered list of every
+	// non-test .go file in the loaded program, and which package each
+	// one belongs to. cache holds the last-computed diagnostics for a
+	// file, so watch mode can invalidate and recompute a single entry
+	// instead of re-walking the whole program.
+	files []string
+	pkgOf map[string]*loader.PackageInfo
+	cache map[string][]diagnostic.Diagnostic
 }
 
-func collectConflicts(node ast.Node) []string {
-	conflicts := []string{}
-	ast.Walk(debugWalker(), node)
-	return conflicts
-}
-
-func checkConflictsWith(attributeName string, def *ast.CompositeLit, schema ast.Node) error {
-	conflicts := []string{}
-
-	for _, elt := range def.Elts {
-		name := elt.(*ast.KeyValueExpr).Key.(*ast.Ident).Name
-		if name != "ConflictsWith" {
-			continue
-		}
-		for _, conflict := range elt.(*ast.KeyValueExpr).Value.(*ast.CompositeLit).Elts {
-			value := conflict.(*ast.BasicLit).Value
-			conflicts = append(conflicts, value[1:len(value)-1])
-		}
+func newResolver(providerPath string, cfg *Config) (*resolver, error) {
+	var conf loader.Config
+	conf.ImportWithTests(providerPath)
+	prog, err := conf.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading %q: %w", providerPath, err)
 	}
-
-	if len(conflicts) == 0 {
-		return nil
+	r := &resolver{
+		prog:       prog,
+		resolved:   map[types.Object]bool{},
+		severities: cfg.enabledRules(),
+		cfg:        cfg,
+		pkgOf:      map[string]*loader.PackageInfo{},
+		cache:      map[string][]diagnostic.Diagnostic{},
 	}
-	attributeNames := collectAttributeNames(schema)
-
-	errors := []error{}
-	for _, conflict := range conflicts {
-		_ = conflict
-		exists := false
-		for _, attribute := range attributeNames {
-			if attribute == conflict {
-				exists = true
-				break
+	for _, pkg := range prog.InitialPackages() {
+		for _, f := range pkg.Files {
+			file := prog.Fset.Position(f.Package).Filename
+			if strings.HasSuffix(file, "_test.go") {
+				continue
 			}
+			r.files = append(r.files, file)
+			r.pkgOf[file] = pkg
 		}
-		if !exists {
-			errors = append(errors, fmt.Errorf("conflict target %q does not exist", conflict))
-		}
-	}
-	if len(errors) == 0 {
-		return nil
 	}
-	errorMessages := []string{}
-	for _, err := range errors {
-		errorMessages = append(errorMessages, err.Error())
-	}
-	return fmt.Errorf("%s: %s", attributeName, strings.Join(errorMessages, ", "))
+	return r, nil
 }
 
-func checkAttributeName(attributeName string, def *ast.CompositeLit, schema ast.Node) error {
-	if attributeName == "id" {
-		return fmt.Errorf("%s: attribute name is reserved", attributeName)
+// identName resolves a schema map key. Most keys are string literals
+// ("name": {...}), but some providers declare them as package-level
+// constants (fieldName: {...}). In that case we use the loader's type
+// info to find the *ast.ValueSpec backing the identifier and pull the
+// literal out of it. It's a free function, not a *resolver method, so
+// collectAttributeNames can resolve the same way without threading a
+// resolver through the whole attribute-existence check.
+func identName(pkg *loader.PackageInfo, key ast.Expr) (string, bool) {
+	switch k := key.(type) {
+	case *ast.BasicLit:
+		return decodeString(k.Value), true
+	case *ast.Ident:
+		obj := pkg.ObjectOf(k)
+		if obj == nil {
+			return "", false
+		}
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
+					if !ok {
+						continue
+					}
+					for i, vsName := range vs.Names {
+						if pkg.ObjectOf(vsName) != obj || i >= len(vs.Values) {
+							continue
+						}
+						lit, ok := vs.Values[i].(*ast.BasicLit)
+						if !ok {
+							continue
+						}
+						return decodeString(lit.Value), true
+					}
+				}
+			}
+		}
+		return "", false
+	case *ast.SelectorExpr:
+		return identName(pkg, k.Sel)
 	}
-	return nil
+	return "", false
 }
 
-var checks = []checkFn{
-	checkFnFunc(checkDescription),
-	checkFnFunc(checkAttributeName),
-	checkFnFunc(checkConflictsWith),
-}
-
-func attributeCollector(res *[]string) schemaWalker {
-	return func(node ast.Node) ast.Visitor {
-		if node == nil {
-			return nil
+// resourceLiteral resolves an expression that should ultimately produce
+// a &schema.Resource{...} composite literal: it's either already one, or
+// a call to a (possibly imported) factory function returning one.
+func (r *resolver) resourceLiteral(pkg *loader.PackageInfo, expr ast.Expr) (*loader.PackageInfo, *ast.CompositeLit, bool) {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok {
+			return pkg, lit, true
+		}
+	case *ast.CallExpr:
+		var obj types.Object
+		switch fn := e.Fun.(type) {
+		case *ast.Ident:
+			obj = pkg.ObjectOf(fn)
+		case *ast.SelectorExpr:
+			obj = pkg.ObjectOf(fn.Sel)
+		}
+		if obj == nil {
+			return nil, nil, false
+		}
+		if r.resolved[obj] {
+			return nil, nil, false
 		}
+		r.resolved[obj] = true
 
-		k, ok := node.(*ast.KeyValueExpr)
-		if !ok {
-			return attributeCollector(res)
+		calleePkg := r.packageInfo(obj.Pkg())
+		if calleePkg == nil {
+			return nil, nil, false
 		}
-		lit, ok := k.Key.(*ast.BasicLit)
-		if !ok {
-			return nil
+		fnDecl := findFuncDecl(calleePkg, obj)
+		if fnDecl == nil || fnDecl.Body == nil {
+			return nil, nil, false
 		}
-		*res = append(*res, lit.Value[1:len(lit.Value)-1])
+		for _, stmt := range fnDecl.Body.List {
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			return r.resourceLiteral(calleePkg, ret.Results[0])
+		}
+	}
+	return nil, nil, false
+}
+
+func (r *resolver) packageInfo(pkg *types.Package) *loader.PackageInfo {
+	if pkg == nil {
 		return nil
 	}
+	return r.prog.AllPackages[pkg]
 }
 
-func attributeChecker(fset *token.FileSet, file string, schema ast.Node) schemaWalker {
-	return func(node ast.Node) ast.Visitor {
+func findFuncDecl(pkg *loader.PackageInfo, obj types.Object) *ast.FuncDecl {
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && pkg.ObjectOf(fn.Name) == obj {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// attributeChecker walks a schema.Schema{...} composite literal,
+// recursing into nested schema.Resource/schema.Schema{Elem: ...}
+// compositions (including ones behind a factory function call) and
+// running every enabled Rule at each level with a dotted path.
+func (r *resolver) attributeChecker(fset *token.FileSet, file, resourceName string, pkg *loader.PackageInfo, prefix string) schemaWalker {
+	var schema ast.Node
+	var walk schemaWalker
+	walk = func(node ast.Node) ast.Visitor {
 		if node == nil {
 			return nil
 		}
+		if schema == nil {
+			// the walk starts at the Schema{...} literal itself
+			schema = node
+		}
 
 		k, ok := node.(*ast.KeyValueExpr)
 		if !ok {
-			return attributeChecker(fset, file, schema)
+			return walk
 		}
-		lit, ok := k.Key.(*ast.BasicLit)
+		name, ok := identName(pkg, k.Key)
 		if !ok {
-			return nil
+			return walk
 		}
 
 		vs, ok := k.Value.(*ast.CompositeLit)
 		if !ok {
-			return attributeChecker(fset, file, schema)
+			return walk
 		}
 
-		for _, check := range checks {
-			err := check(lit.Value, vs, schema)
-			if err != nil {
-				fmt.Printf("%s:%#v %s\n", strings.Replace(file, *providerPath, "", -1), fset.Position(node.Pos()).Line, err.Error())
-			}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
 		}
 
-		return attributeChecker(fset, file, schema)
-	}
-}
-
-func schemaChecker(fset *token.FileSet, file string) schemaWalker {
-	return func(node ast.Node) ast.Visitor {
-		if node == nil {
-			return nil
-		}
-		c, ok := node.(*ast.CompositeLit)
-		if !ok {
-			return schemaChecker(fset, file)
+		ctx := &Context{ResourceName: resourceName, Path: path, Def: vs, Schema: schema, Pkg: pkg}
+		start := fset.Position(node.Pos())
+		end := fset.Position(vs.End())
+		for id, severity := range r.severities {
+			if r.cfg.ignored(id, resourceName, path) {
+				continue
+			}
+			rule := registry[id]
+			for _, d := range rule.Check(ctx) {
+				d.File = strings.Replace(file, *providerPath, "", -1)
+				d.Line = start.Line
+				d.Column = start.Column
+				d.EndLine = end.Line
+				d.EndColumn = end.Column
+				d.Severity = severity
+				r.diags = append(r.diags, d)
+			}
 		}
-		if c.Type == nil {
-			return schemaChecker(fset, file)
+
+		if elemPkg, elemLit, nested := r.nestedSchema(pkg, vs); nested {
+			ast.Walk(r.attributeChecker(fset, file, resourceName, elemPkg, path), elemLit)
 		}
-		return attributeChecker(fset, file, c)
+
+		return nil
 	}
+	return walk
 }
 
-func schemaResourceChecker(fset *token.FileSet, file string) schemaWalker {
-	return func(node ast.Node) ast.Visitor {
-		if node == nil {
-			return nil
+// nestedSchema finds the Elem: ... entry of an attribute definition and,
+// if it is itself a *schema.Resource (directly, or via a factory
+// function), returns the Schema{...} composite literal to recurse into.
+func (r *resolver) nestedSchema(pkg *loader.PackageInfo, def *ast.CompositeLit) (*loader.PackageInfo, *ast.CompositeLit, bool) {
+	for _, elt := range def.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok || kv.Key.(*ast.Ident).Name != "Elem" {
+			continue
 		}
-		kv, ok := node.(*ast.KeyValueExpr)
+		elemPkg, resourceLit, ok := r.resourceLiteral(pkg, kv.Value)
 		if !ok {
-			return schemaResourceChecker(fset, file)
+			return nil, nil, false
 		}
-
-		if v, ok := kv.Key.(*ast.Ident); !ok || v.Name != "Schema" {
-			return schemaResourceChecker(fset, file)
+		for _, resElt := range resourceLit.Elts {
+			resKV, ok := resElt.(*ast.KeyValueExpr)
+			if !ok || resKV.Key.(*ast.Ident).Name != "Schema" {
+				continue
+			}
+			schemaLit, ok := resKV.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			return elemPkg, schemaLit, true
 		}
-		return schemaChecker(fset, file)
 	}
+	return nil, nil, false
 }
 
-func schemaFinder(fset *token.FileSet, file string) schemaWalker {
+func (r *resolver) schemaFinder(fset *token.FileSet, file string, pkg *loader.PackageInfo) schemaWalker {
 	return func(node ast.Node) ast.Visitor {
 		if node == nil {
 			return nil
@@ -210,12 +323,9 @@ func schemaFinder(fset *token.FileSet, file string) schemaWalker {
 
 		fn, ok := node.(*ast.FuncDecl)
 		if !ok {
-			return schemaFinder(fset, file)
+			return r.schemaFinder(fset, file, pkg)
 		}
-		if fn.Type.Results == nil {
-			return nil
-		}
-		if len(fn.Type.Results.List) != 1 {
+		if fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
 			return nil
 		}
 		ret, ok := fn.Type.Results.List[0].Type.(*ast.StarExpr)
@@ -229,39 +339,168 @@ func schemaFinder(fset *token.FileSet, file string) schemaWalker {
 		if sel.Sel.Name != "Resource" || sel.X.(*ast.Ident).Name != "schema" {
 			return nil
 		}
-		return schemaResourceChecker(fset, file)
+
+		for _, stmt := range fn.Body.List {
+			retStmt, ok := stmt.(*ast.ReturnStmt)
+			if !ok || len(retStmt.Results) != 1 {
+				continue
+			}
+			// Reset per top-level resource: resolved is only meant to
+			// stop a single resource's factory chain from recursing into
+			// itself, not to suppress a shared helper (e.g. tagsSchema())
+			// the second time a different resource calls it.
+			r.resolved = map[types.Object]bool{}
+			_, resourceLit, ok := r.resourceLiteral(pkg, retStmt.Results[0])
+			if !ok {
+				continue
+			}
+			for _, elt := range resourceLit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok || kv.Key.(*ast.Ident).Name != "Schema" {
+					continue
+				}
+				schemaLit, ok := kv.Value.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				ast.Walk(r.attributeChecker(fset, file, fn.Name.Name, pkg, ""), schemaLit)
+			}
+		}
+		return nil
+	}
+}
+
+// runAll re-walks every already-loaded package and returns a fresh
+// diagnostic set. It's the one-shot main() path and fills the per-file
+// cache that watch mode then updates one file at a time via runFile.
+func (r *resolver) runAll() []diagnostic.Diagnostic {
+	for _, pkg := range r.prog.InitialPackages() {
+		for _, f := range pkg.Files {
+			file := r.prog.Fset.Position(f.Package).Filename
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			r.cache[file] = r.walkFile(pkg, file, f)
+		}
+	}
+	return r.flatten()
+}
+
+// walkFile runs schemaFinder over a single already-parsed file and
+// returns just the diagnostics it produced, without disturbing any
+// diagnostics accumulated for other files.
+func (r *resolver) walkFile(pkg *loader.PackageInfo, file string, f *ast.File) []diagnostic.Diagnostic {
+	saved := r.diags
+	r.diags = nil
+	ast.Walk(r.schemaFinder(r.prog.Fset, file, pkg), f)
+	diags := r.diags
+	r.diags = saved
+	return diags
+}
+
+// flatten concatenates the per-file cache in a fixed file order. Within
+// a file the order follows a map iteration over the enabled rules and
+// can differ between walks, which is why equalDiagnostics ignores order.
+func (r *resolver) flatten() []diagnostic.Diagnostic {
+	all := []diagnostic.Diagnostic{}
+	for _, file := range r.files {
+		all = append(all, r.cache[file]...)
 	}
+	return all
+}
+
+// invalidate drops a file's cached diagnostics; the next flatten (after
+// runFile repopulates it) won't see stale results for that file.
+func (r *resolver) invalidate(file string) {
+	delete(r.cache, file)
 }
 
-func checkSchema(path string) {
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+// runFile re-parses a single file from disk and re-runs schemaFinder
+// against just that file, updating its cache entry. The program's type
+// information still belongs to the original parse, so identifiers in the
+// reparsed file don't resolve: attribute keys that are const/var
+// identifiers and Elem values that call a factory function are skipped
+// for that file until a process restart. String-literal keys and inline
+// Elem blocks, the common case of editing a Schema literal in place, are
+// checked.
+func (r *resolver) runFile(file string) {
+	pkg, ok := r.pkgOf[file]
+	if !ok {
+		log.Printf("%s is not part of the loaded program; restart to pick up new files", file)
+		return
+	}
+
+	f, err := parser.ParseFile(r.prog.Fset, file, nil, parser.ParseComments)
 	if err != nil {
-		log.Fatal(err.Error())
+		log.Printf("failed to reparse %s: %v", file, err)
+		return
+	}
+	for i, old := range pkg.Files {
+		if r.prog.Fset.Position(old.Package).Filename == file {
+			pkg.Files[i] = f
+			break
+		}
 	}
-	ast.Walk(schemaFinder(fset, path), f)
+
+	r.cache[file] = r.walkFile(pkg, file, f)
+}
+
+func decodeString(val string) string {
+	return val[1 : len(val)-1]
 }
 
-func init() {
+func main() {
 	providerPath = flag.String("provider-path", "", "path to the terraform provider to check")
 	debug = flag.Bool("debug", false, "enable debug output")
+	format = flag.String("format", "text", "diagnostic output format: text, json, sarif, checkstyle, github-actions")
+	configPath = flag.String("config", "", "path to .tfschemalint.yaml, or its containing directory (defaults to provider-path)")
+	watch = flag.Bool("watch", false, "watch provider-path for changes and re-check incrementally")
 	flag.Parse()
 
 	if providerPath == nil || *providerPath == "" {
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
-}
 
-func main() {
-	filepath.Walk(*providerPath, func(path string, info os.FileInfo, err error) error {
-		if strings.HasSuffix(path, "_test.go") {
-			return nil
+	cfgDir := *providerPath
+	if *configPath != "" {
+		cfgDir = *configPath
+	}
+	cfg, err := loadConfig(cfgDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r, err := newResolver(*providerPath, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	describe := func(ruleID string) string {
+		if rule, ok := registry[ruleID]; ok {
+			return rule.Description()
 		}
-		if !strings.HasSuffix(path, ".go") {
-			return nil
+		return ""
+	}
+	reporter, err := diagnostic.ReporterFor(*format, describe)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	diags := r.runAll()
+	if err := reporter.Report(os.Stdout, diags); err != nil {
+		log.Fatal(err)
+	}
+
+	if *watch {
+		log.Printf("watching %q for changes", *providerPath)
+		emit := func(diags []diagnostic.Diagnostic) {
+			if err := reporter.Report(os.Stdout, diags); err != nil {
+				log.Printf("reporting diagnostics: %v", err)
+			}
 		}
-		checkSchema(path)
-		return nil
-	})
+		if err := watchAndRerun(*providerPath, r, diags, emit); err != nil {
+			log.Fatalf("watch failed: %v", err)
+		}
+	}
 }
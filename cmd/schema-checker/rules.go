@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/loader"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
+)
+
+// Context carries everything a Rule needs to inspect a single attribute
+// definition: its dotted path within the resource, the resource it
+// belongs to, and the enclosing schema so rules like conflicts-with-target
+// can look up sibling attributes. Pkg carries the type-checked package
+// the schema was found in, so referent checks can resolve attribute keys
+// that are const/var identifiers the same way the walker itself does.
+type Context struct {
+	ResourceName string
+	Path         string
+	Def          *ast.CompositeLit
+	Schema       ast.Node
+	Pkg          *loader.PackageInfo
+}
+
+// Rule is a single registered check. Rules are looked up by ID so a
+// .tfschemalint.yaml config can enable/disable and re-level them without
+// touching code.
+type Rule interface {
+	ID() string
+	Description() string
+	Severity() diagnostic.Severity
+	Check(ctx *Context) []diagnostic.Diagnostic
+}
+
+var registry = map[string]Rule{}
+
+// Register adds a Rule under name to the global registry. Built-in rules
+// register themselves from this file's init(); further rules can do the
+// same from an init() in another file of this package.
+func Register(name string, rule Rule) {
+	registry[name] = rule
+}
+
+type ruleFunc struct {
+	id          string
+	description string
+	severity    diagnostic.Severity
+	check       func(rule Rule, ctx *Context) []diagnostic.Diagnostic
+}
+
+func (r *ruleFunc) ID() string                    { return r.id }
+func (r *ruleFunc) Description() string           { return r.description }
+func (r *ruleFunc) Severity() diagnostic.Severity { return r.severity }
+func (r *ruleFunc) Check(ctx *Context) []diagnostic.Diagnostic {
+	return r.check(r, ctx)
+}
+
+func diag(rule Rule, ctx *Context, format string, a ...interface{}) diagnostic.Diagnostic {
+	return diagnostic.Diagnostic{
+		RuleID:        rule.ID(),
+		Severity:      rule.Severity(),
+		Message:       fmt.Sprintf(format, a...),
+		ResourceName:  ctx.ResourceName,
+		AttributePath: ctx.Path,
+	}
+}
+
+func register(id, description string, severity diagnostic.Severity, check func(rule Rule, ctx *Context) []diagnostic.Diagnostic) {
+	Register(id, &ruleFunc{id: id, description: description, severity: severity, check: check})
+}
+
+func init() {
+	register("description", "every attribute must document itself with a Description", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			for _, elt := range ctx.Def.Elts {
+				if key(elt) == "Description" {
+					return nil
+				}
+			}
+			return []diagnostic.Diagnostic{diag(rule, ctx, "%s: Missing Description attribute", ctx.Path)}
+		})
+
+	register("reserved-attribute-name", `"id" is reserved by Terraform core and must not be declared in Schema`, diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			if ctx.Path == "id" || strings.HasSuffix(ctx.Path, ".id") {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: attribute name is reserved", ctx.Path)}
+			}
+			return nil
+		})
+
+	register("conflicts-with-target", "every ConflictsWith entry must name an attribute that exists in the resource", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			return checkReferentsExist(rule, ctx, "ConflictsWith")
+		})
+
+	register("at-least-one-of-target", "every AtLeastOneOf/ExactlyOneOf entry must name an attribute that exists in the resource", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			return checkReferentsExist(rule, ctx, "AtLeastOneOf", "ExactlyOneOf")
+		})
+
+	register("force-new-computed-only", "ForceNew has no effect on an attribute that is Computed-only, since the user never sets it", diagnostic.Warning,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			flags := boolFlags(ctx.Def)
+			if flags["ForceNew"] && flags["Computed"] && !flags["Optional"] && !flags["Required"] {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: ForceNew is set on a Computed-only attribute", ctx.Path)}
+			}
+			return nil
+		})
+
+	register("sensitive-non-string", "Sensitive masking only applies to schema.TypeString attributes", diagnostic.Warning,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			if !boolFlags(ctx.Def)["Sensitive"] {
+				return nil
+			}
+			if schemaType(ctx.Def) != "TypeString" {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: Sensitive set on a non-string attribute", ctx.Path)}
+			}
+			return nil
+		})
+
+	register("default-with-required", "Default is never applied to a Required attribute, since the user must always supply a value", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			hasDefault := false
+			for _, elt := range ctx.Def.Elts {
+				if key(elt) == "Default" {
+					hasDefault = true
+				}
+			}
+			if hasDefault && boolFlags(ctx.Def)["Required"] {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: Default is set alongside Required", ctx.Path)}
+			}
+			return nil
+		})
+
+	register("validate-func-conflict", "ValidateFunc and ValidateDiagFunc are mutually exclusive", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			hasValidateFunc, hasValidateDiagFunc := false, false
+			for _, elt := range ctx.Def.Elts {
+				switch key(elt) {
+				case "ValidateFunc":
+					hasValidateFunc = true
+				case "ValidateDiagFunc":
+					hasValidateDiagFunc = true
+				}
+			}
+			if hasValidateFunc && hasValidateDiagFunc {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: both ValidateFunc and ValidateDiagFunc are set", ctx.Path)}
+			}
+			return nil
+		})
+
+	register("deprecated-missing-message", "a Deprecated attribute must explain what to use instead", diagnostic.Warning,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			for _, elt := range ctx.Def.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok || key(elt) != "Deprecated" {
+					continue
+				}
+				if lit, ok := kv.Value.(*ast.BasicLit); ok && decodeString(lit.Value) == "" {
+					return []diagnostic.Diagnostic{diag(rule, ctx, "%s: Deprecated is set without an explanatory message", ctx.Path)}
+				}
+			}
+			return nil
+		})
+
+	register("conflicts-with-required", "Required and ConflictsWith are mutually exclusive: a Required attribute can never be omitted in favor of its conflict", diagnostic.Error,
+		func(rule Rule, ctx *Context) []diagnostic.Diagnostic {
+			flags := boolFlags(ctx.Def)
+			hasConflictsWith := false
+			for _, elt := range ctx.Def.Elts {
+				if key(elt) == "ConflictsWith" {
+					hasConflictsWith = true
+				}
+			}
+			if hasConflictsWith && flags["Required"] {
+				return []diagnostic.Diagnostic{diag(rule, ctx, "%s: Required is set alongside ConflictsWith", ctx.Path)}
+			}
+			return nil
+		})
+}
+
+func key(elt ast.Expr) string {
+	kv, ok := elt.(*ast.KeyValueExpr)
+	if !ok {
+		return ""
+	}
+	id, ok := kv.Key.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return id.Name
+}
+
+func boolFlags(def *ast.CompositeLit) map[string]bool {
+	flags := map[string]bool{}
+	for _, elt := range def.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		id, ok := kv.Value.(*ast.Ident)
+		if !ok || id.Name != "true" {
+			continue
+		}
+		flags[key(elt)] = true
+	}
+	return flags
+}
+
+func schemaType(def *ast.CompositeLit) string {
+	for _, elt := range def.Elts {
+		if key(elt) != "Type" {
+			continue
+		}
+		kv := elt.(*ast.KeyValueExpr)
+		sel, ok := kv.Value.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// checkReferentsExist validates every string in the named
+// []string-valued fields (ConflictsWith, AtLeastOneOf, ExactlyOneOf, …)
+// against the attribute names declared directly in ctx.Schema. Names
+// inside nested Elem blocks are not collected, so a dotted referent such
+// as "block.0.attr" is reported as missing.
+func checkReferentsExist(rule Rule, ctx *Context, fieldNames ...string) []diagnostic.Diagnostic {
+	wanted := map[string]bool{}
+	for _, f := range fieldNames {
+		wanted[f] = true
+	}
+
+	referents := []string{}
+	for _, elt := range ctx.Def.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok || !wanted[key(elt)] {
+			continue
+		}
+		lit, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, v := range lit.Elts {
+			basic, ok := v.(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			referents = append(referents, decodeString(basic.Value))
+		}
+	}
+	if len(referents) == 0 {
+		return nil
+	}
+
+	attributeNames := collectAttributeNames(ctx.Pkg, ctx.Schema)
+	diags := []diagnostic.Diagnostic{}
+	for _, referent := range referents {
+		exists := false
+		for _, attribute := range attributeNames {
+			if attribute == referent || strings.HasPrefix(attribute, referent+".") {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			diags = append(diags, diag(rule, ctx, "%s: target %q does not exist", ctx.Path, referent))
+		}
+	}
+	return diags
+}
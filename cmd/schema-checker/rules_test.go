@@ -0,0 +1,106 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// parseAttrDef parses src as a package-level var declaration and returns
+// the *ast.CompositeLit of the single schema attribute definition in it,
+// e.g. src containing `{Type: schema.TypeString, Required: true}`.
+func parseAttrDef(t *testing.T, src string) *ast.CompositeLit {
+	t.Helper()
+	full := "package p\nvar x = map[string]*schema.Schema{\n\"attr\": " + src + ",\n}\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "test.go", full, 0)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	decl := f.Decls[0].(*ast.GenDecl)
+	spec := decl.Specs[0].(*ast.ValueSpec)
+	mapLit := spec.Values[0].(*ast.CompositeLit)
+	kv := mapLit.Elts[0].(*ast.KeyValueExpr)
+	return kv.Value.(*ast.CompositeLit)
+}
+
+func check(t *testing.T, ruleID, src string) []string {
+	t.Helper()
+	rule, ok := registry[ruleID]
+	if !ok {
+		t.Fatalf("rule %q not registered", ruleID)
+	}
+	ctx := &Context{ResourceName: "test_thing", Path: "attr", Def: parseAttrDef(t, src)}
+	var msgs []string
+	for _, d := range rule.Check(ctx) {
+		msgs = append(msgs, d.Message)
+	}
+	return msgs
+}
+
+func TestCheckDescription(t *testing.T) {
+	if got := check(t, "description", `{Type: schema.TypeString, Required: true}`); len(got) != 1 {
+		t.Fatalf("expected a missing-description diagnostic, got %v", got)
+	}
+	if got := check(t, "description", `{Type: schema.TypeString, Required: true, Description: "the attr"}`); len(got) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", got)
+	}
+}
+
+func TestCheckReservedAttributeName(t *testing.T) {
+	rule := registry["reserved-attribute-name"]
+	ctx := &Context{ResourceName: "test_thing", Path: "id", Def: parseAttrDef(t, `{Type: schema.TypeString, Computed: true}`)}
+	if got := rule.Check(ctx); len(got) != 1 {
+		t.Fatalf("expected a reserved-name diagnostic for %q, got %v", ctx.Path, got)
+	}
+	ctx.Path = "name"
+	if got := rule.Check(ctx); len(got) != 0 {
+		t.Fatalf("expected no diagnostics for %q, got %v", ctx.Path, got)
+	}
+}
+
+func TestCheckForceNewComputedOnly(t *testing.T) {
+	if got := check(t, "force-new-computed-only", `{Type: schema.TypeString, Computed: true, ForceNew: true}`); len(got) != 1 {
+		t.Fatalf("expected ForceNew-on-Computed-only diagnostic, got %v", got)
+	}
+	if got := check(t, "force-new-computed-only", `{Type: schema.TypeString, Optional: true, ForceNew: true}`); len(got) != 0 {
+		t.Fatalf("expected no diagnostics for an Optional+ForceNew attribute, got %v", got)
+	}
+}
+
+func TestCheckSensitiveNonString(t *testing.T) {
+	if got := check(t, "sensitive-non-string", `{Type: schema.TypeInt, Optional: true, Sensitive: true}`); len(got) != 1 {
+		t.Fatalf("expected Sensitive-on-non-string diagnostic, got %v", got)
+	}
+	if got := check(t, "sensitive-non-string", `{Type: schema.TypeString, Optional: true, Sensitive: true}`); len(got) != 0 {
+		t.Fatalf("expected no diagnostics for Sensitive on a string, got %v", got)
+	}
+}
+
+func TestCheckDefaultWithRequired(t *testing.T) {
+	if got := check(t, "default-with-required", `{Type: schema.TypeString, Required: true, Default: "x"}`); len(got) != 1 {
+		t.Fatalf("expected Default-with-Required diagnostic, got %v", got)
+	}
+}
+
+func TestCheckValidateFuncConflict(t *testing.T) {
+	if got := check(t, "validate-func-conflict", `{Type: schema.TypeString, Optional: true, ValidateFunc: validate, ValidateDiagFunc: validateDiag}`); len(got) != 1 {
+		t.Fatalf("expected ValidateFunc/ValidateDiagFunc conflict diagnostic, got %v", got)
+	}
+}
+
+func TestCheckDeprecatedMissingMessage(t *testing.T) {
+	if got := check(t, "deprecated-missing-message", `{Type: schema.TypeString, Optional: true, Deprecated: ""}`); len(got) != 1 {
+		t.Fatalf("expected deprecated-missing-message diagnostic, got %v", got)
+	}
+	if got := check(t, "deprecated-missing-message", `{Type: schema.TypeString, Optional: true, Deprecated: "use other_attr instead"}`); len(got) != 0 {
+		t.Fatalf("expected no diagnostics when Deprecated has a message, got %v", got)
+	}
+}
+
+func TestCheckConflictsWithRequired(t *testing.T) {
+	if got := check(t, "conflicts-with-required", `{Type: schema.TypeString, Required: true, ConflictsWith: []string{"other"}}`); len(got) != 1 {
+		t.Fatalf("expected Required+ConflictsWith diagnostic, got %v", got)
+	}
+}
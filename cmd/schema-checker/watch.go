@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/nicolai86/terraform-tools/diagnostic"
+)
+
+// debounceWindow coalesces editor save bursts into a single re-check.
+const debounceWindow = 200 * time.Millisecond
+
+// watchAndRerun observes root for .go writes and, per file and
+// debounced, invalidates and re-runs just that file's entry in
+// r.cache (mirroring doc-checker's per-file Runner) instead of
+// resolver.runAll's full-program re-walk. emit is only called when the
+// resulting diagnostic set actually differs from the last one reported.
+//
+// go/loader type-checks the whole program up front into an immutable
+// *loader.Program, so the reparsed file's identifiers don't resolve at
+// all: in that file, const/var attribute keys and factory-function Elem
+// values are skipped until a process restart. Editing string-keyed
+// Schema literals and inline Elem blocks in place is cheap and picked up.
+func watchAndRerun(root string, r *resolver, last []diagnostic.Diagnostic, emit func([]diagnostic.Diagnostic)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := addRecursive(watcher, root); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	timers := map[string]*time.Timer{}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if !strings.HasSuffix(event.Name, ".go") {
+				continue
+			}
+
+			path := event.Name
+			mu.Lock()
+			if t, ok := timers[path]; ok {
+				t.Stop()
+			}
+			timers[path] = time.AfterFunc(debounceWindow, func() {
+				r.invalidate(path)
+				r.runFile(path)
+				diags := r.flatten()
+
+				mu.Lock()
+				changed := !equalDiagnostics(last, diags)
+				last = diags
+				mu.Unlock()
+				if changed {
+					emit(diags)
+				}
+			})
+			mu.Unlock()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("watch error: %v", err)
+		}
+	}
+}
+
+func addRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
+
+// equalDiagnostics compares a and b as multisets: the order of
+// diagnostics within a file is not stable between walks.
+func equalDiagnostics(a, b []diagnostic.Diagnostic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[diagnostic.Diagnostic]int{}
+	for _, d := range a {
+		counts[d]++
+	}
+	for _, d := range b {
+		if counts[d] == 0 {
+			return false
+		}
+		counts[d]--
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// finding is a single check violation against an in-memory buffer,
+// carrying enough position info to become both a Diagnostic and (for
+// rules that support it) a CodeAction.
+type finding struct {
+	pos     token.Pos
+	end     token.Pos
+	ruleID  string
+	message string
+	// insertAfter/replace describe the mechanical fix, if any; exactly
+	// one of them is set by rules that are auto-fixable.
+	insertAfter token.Pos
+	insertText  string
+	replacePos  token.Pos
+	replaceEnd  token.Pos
+	replaceText string
+}
+
+// lint runs the same checks schema-checker applies (Description is
+// present, the reserved "id" key isn't used, ConflictsWith targets
+// exist), but against a single in-memory *ast.File with no cross-package
+// type information: an editor buffer doesn't have a loadable program
+// while the user is mid-edit, so, unlike schema-checker's resolver,
+// this only understands single-file, non-factory schema.Resource
+// literals. Nested Elem blocks aren't walked for the same reason.
+func lint(fset *token.FileSet, f *ast.File) []finding {
+	findings := []finding{}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+			continue
+		}
+		ret, ok := fn.Type.Results.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := ret.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Resource" || sel.X.(*ast.Ident).Name != "schema" {
+			continue
+		}
+
+		for _, stmt := range fn.Body.List {
+			retStmt, ok := stmt.(*ast.ReturnStmt)
+			if !ok || len(retStmt.Results) != 1 {
+				continue
+			}
+			unary, ok := retStmt.Results[0].(*ast.UnaryExpr)
+			if !ok {
+				continue
+			}
+			resourceLit, ok := unary.X.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, elt := range resourceLit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok || kv.Key.(*ast.Ident).Name != "Schema" {
+					continue
+				}
+				schemaLit, ok := kv.Value.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				findings = append(findings, lintSchema(schemaLit)...)
+			}
+		}
+	}
+
+	return findings
+}
+
+func lintSchema(schemaLit *ast.CompositeLit) []finding {
+	names := []string{}
+	for _, elt := range schemaLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if lit, ok := kv.Key.(*ast.BasicLit); ok {
+			names = append(names, decodeString(lit.Value))
+		}
+	}
+
+	findings := []finding{}
+	for _, elt := range schemaLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		lit, ok := kv.Key.(*ast.BasicLit)
+		if !ok {
+			continue
+		}
+		name := decodeString(lit.Value)
+		def, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		if name == "id" {
+			findings = append(findings, finding{
+				pos:         lit.Pos(),
+				end:         lit.End(),
+				ruleID:      "reserved-attribute-name",
+				message:     fmt.Sprintf("%s: attribute name is reserved", name),
+				replacePos:  lit.Pos(),
+				replaceEnd:  lit.End(),
+				replaceText: `"resource_id"`,
+			})
+		}
+
+		hasDescription := false
+		for _, fieldElt := range def.Elts {
+			fieldKV, ok := fieldElt.(*ast.KeyValueExpr)
+			if ok && fieldKV.Key.(*ast.Ident).Name == "Description" {
+				hasDescription = true
+			}
+		}
+		if !hasDescription {
+			findings = append(findings, finding{
+				pos:         def.Pos(),
+				end:         def.End(),
+				ruleID:      "missing-description",
+				message:     fmt.Sprintf("%s: Missing Description attribute", name),
+				insertAfter: def.Lbrace,
+				insertText:  ` Description: "TODO",`,
+			})
+		}
+
+		for _, fieldElt := range def.Elts {
+			fieldKV, ok := fieldElt.(*ast.KeyValueExpr)
+			if !ok || fieldKV.Key.(*ast.Ident).Name != "ConflictsWith" {
+				continue
+			}
+			targets, ok := fieldKV.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, t := range targets.Elts {
+				tlit, ok := t.(*ast.BasicLit)
+				if !ok {
+					continue
+				}
+				target := decodeString(tlit.Value)
+				if !contains(names, target) {
+					findings = append(findings, finding{
+						pos:     tlit.Pos(),
+						end:     tlit.End(),
+						ruleID:  "conflicts-with-target",
+						message: fmt.Sprintf("%s: ConflictsWith target %q does not exist", name, target),
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeString(val string) string {
+	return strings.Trim(val, `"`)
+}
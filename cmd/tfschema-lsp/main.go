@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	logPath      *string
+	docGenerator *string
+)
+
+// document is the server's view of one open buffer: its latest text and
+// the parsed AST it produced, kept around so codeAction can work off
+// the same findings publishDiagnostics already computed.
+type document struct {
+	uri      string
+	text     string
+	fset     *token.FileSet
+	file     *ast.File
+	findings []finding
+}
+
+type server struct {
+	c            *conn
+	providerRoot string
+	docs         map[string]*document
+}
+
+func init() {
+	logPath = flag.String("log", "", "write server logs to this file instead of stderr")
+	docGenerator = flag.String("doc-generator", "doc-generator", "path to the doc-generator binary used by the regenerate-docs command")
+	flag.Parse()
+
+	if *logPath != "" {
+		f, err := os.OpenFile(*logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Fatalf("opening log file: %v", err)
+		}
+		log.SetOutput(f)
+	}
+}
+
+func main() {
+	s := &server{c: newConn(os.Stdin, os.Stdout), docs: map[string]*document{}}
+	if err := s.run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (s *server) run() error {
+	for {
+		msg, err := s.c.read()
+		if err != nil {
+			return err
+		}
+		if msg.Method == "" {
+			continue // a response to a request we never send
+		}
+		if err := s.handle(msg); err != nil {
+			log.Printf("handling %s: %v", msg.Method, err)
+		}
+	}
+}
+
+func (s *server) handle(msg *message) error {
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		s.providerRoot = detectProviderRoot(uriToPath(params.RootURI))
+		return s.c.reply(msg.ID, InitializeResult{Capabilities: ServerCapabilities{
+			TextDocumentSync:   1, // full sync
+			CodeActionProvider: true,
+			ExecuteCommandProvider: &ExecuteCommandOptions{
+				Commands: []string{"tfschema.regenerateDocs"},
+			},
+		}})
+	case "initialized", "textDocument/didSave":
+		return nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		return s.lintAndPublish(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		return s.lintAndPublish(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil
+	case "textDocument/codeAction":
+		var params CodeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		return s.c.reply(msg.ID, s.codeActions(params))
+	case "workspace/executeCommand":
+		var params ExecuteCommandParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		return s.executeCommand(msg.ID, params)
+	case "shutdown":
+		return s.c.reply(msg.ID, nil)
+	case "exit":
+		os.Exit(0)
+	}
+	return nil
+}
+
+func (s *server) lintAndPublish(uri, text string) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, uriToPath(uri), text, parser.ParseComments)
+	if err != nil {
+		// a buffer mid-edit is often syntactically invalid; that's not
+		// our problem to report, gopls already does that.
+		return s.c.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: nil})
+	}
+
+	findings := lint(fset, f)
+	s.docs[uri] = &document{uri: uri, text: text, fset: fset, file: f, findings: findings}
+
+	diags := make([]Diagnostic, 0, len(findings))
+	for _, fd := range findings {
+		diags = append(diags, Diagnostic{
+			Range:    rangeOf(fset, fd.pos, fd.end),
+			Severity: DiagnosticSeverityWarning,
+			Source:   "tfschema-lsp",
+			Message:  fd.message,
+		})
+	}
+	return s.c.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: diags})
+}
+
+// codeActions offers mechanical fixes for the findings whose range
+// overlaps the request, plus a standing "regenerate docs" entry. That
+// entry carries neither an edit nor a command, so choosing it changes
+// nothing; docs are regenerated through the tfschema.regenerateDocs
+// command instead.
+func (s *server) codeActions(params CodeActionParams) []CodeAction {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	actions := []CodeAction{}
+	for _, fd := range doc.findings {
+		r := rangeOf(doc.fset, fd.pos, fd.end)
+		if !overlaps(r, params.Range) {
+			continue
+		}
+		switch fd.ruleID {
+		case "missing-description":
+			actions = append(actions, CodeAction{
+				Title: "Insert Description: \"TODO\"",
+				Kind:  "quickfix",
+				Edit: &WorkspaceEdit{Changes: map[string][]TextEdit{
+					params.TextDocument.URI: {{
+						Range:   rangeOf(doc.fset, fd.insertAfter+1, fd.insertAfter+1),
+						NewText: fd.insertText,
+					}},
+				}},
+			})
+		case "reserved-attribute-name":
+			actions = append(actions, CodeAction{
+				Title: "Rename reserved \"id\" attribute",
+				Kind:  "quickfix",
+				Edit: &WorkspaceEdit{Changes: map[string][]TextEdit{
+					params.TextDocument.URI: {{
+						Range:   rangeOf(doc.fset, fd.replacePos, fd.replaceEnd),
+						NewText: fd.replaceText,
+					}},
+				}},
+			})
+		}
+	}
+
+	actions = append(actions, CodeAction{
+		Title: "Regenerate docs for this resource",
+		Kind:  "source",
+		Edit:  nil,
+	})
+	return actions
+}
+
+func (s *server) executeCommand(id *json.RawMessage, params ExecuteCommandParams) error {
+	if params.Command != "tfschema.regenerateDocs" {
+		return s.c.replyError(id, -32601, "unknown command %q", params.Command)
+	}
+	if s.providerRoot == "" {
+		return s.c.replyError(id, -32602, "no provider root detected")
+	}
+
+	providerName := filepath.Base(s.providerRoot)
+	cmd := exec.Command(*docGenerator, "-provider-path", s.providerRoot, "-provider-name", providerName, "-write")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return s.c.replyError(id, -32000, "doc-generator failed: %v: %s", err, out)
+	}
+	return s.c.reply(id, nil)
+}
+
+// detectProviderRoot walks up from start looking for a provider.go that
+// declares a Provider() function, the same signal doc-checker's
+// parseProviderDefinition keys off.
+func detectProviderRoot(start string) string {
+	dir := start
+	for i := 0; i < 32; i++ {
+		candidate := filepath.Join(dir, "provider.go")
+		if declaresProvider(candidate) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ""
+}
+
+func declaresProvider(path string) bool {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return false
+	}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Name.Name == "Provider" {
+			return true
+		}
+	}
+	return false
+}
+
+func uriToPath(uri string) string {
+	return strings.TrimPrefix(uri, "file://")
+}
+
+func rangeOf(fset *token.FileSet, start, end token.Pos) Range {
+	s := fset.Position(start)
+	e := fset.Position(end)
+	return Range{
+		Start: Position{Line: s.Line - 1, Character: s.Column - 1},
+		End:   Position{Line: e.Line - 1, Character: e.Column - 1},
+	}
+}
+
+func overlaps(a, b Range) bool {
+	if a.End.Line < b.Start.Line || b.End.Line < a.Start.Line {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// message is the on-the-wire shape shared by requests, responses and
+// notifications: a Request has Method+ID, a Notification has Method
+// with no ID, a Response has ID+Result/Error and no Method.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *rpcError        `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// conn speaks the LSP wire protocol (JSON-RPC 2.0 framed with
+// Content-Length headers) over an arbitrary io.Reader/io.Writer pair,
+// so it can be driven by stdio in main() and by tests directly.
+type conn struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+func (c *conn) read() (*message, error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Content-Length:")))
+			if err != nil {
+				return nil, fmt.Errorf("bad Content-Length header %q: %w", line, err)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("message missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, err
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *conn) write(msg message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *conn) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(message{Method: method, Params: raw})
+}
+
+func (c *conn) reply(id *json.RawMessage, result interface{}) error {
+	return c.write(message{ID: id, Result: result})
+}
+
+func (c *conn) replyError(id *json.RawMessage, code int, format string, a ...interface{}) error {
+	return c.write(message{ID: id, Error: &rpcError{Code: code, Message: fmt.Sprintf(format, a...)}})
+}
@@ -0,0 +1,62 @@
+package diagnostic
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// checkstyleReporter renders the Checkstyle XML format, understood by
+// most CI dashboards and IDE problem matchers that predate SARIF.
+type checkstyleReporter struct{}
+
+type checkstyleXML struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (checkstyleReporter) Report(w io.Writer, diagnostics []Diagnostic) error {
+	byFile := map[string][]checkstyleError{}
+	order := []string{}
+	for _, d := range diagnostics {
+		if _, ok := byFile[d.File]; !ok {
+			order = append(order, d.File)
+		}
+		byFile[d.File] = append(byFile[d.File], checkstyleError{
+			Line:     d.Line,
+			Column:   d.Column,
+			Severity: checkstyleSeverity(d.Severity),
+			Message:  d.Message,
+			Source:   d.RuleID,
+		})
+	}
+
+	out := checkstyleXML{Version: "4.3"}
+	for _, file := range order {
+		out.Files = append(out.Files, checkstyleFile{Name: file, Errors: byFile[file]})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
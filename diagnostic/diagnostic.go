@@ -0,0 +1,56 @@
+// Package diagnostic is the shared machine-readable result shape for
+// this repo's checkers: schema-checker and doc-checker both produce
+// Diagnostics and render them through the same set of Reporters, so a
+// CI pipeline can point either one at -format=sarif or
+// -format=github-actions without caring which tool found the problem.
+package diagnostic
+
+// Severity classifies how serious a Diagnostic is. The zero value is
+// Error so a Diagnostic built without setting one still fails a CI
+// check rather than being silently informational.
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Info
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warn"
+	case Info:
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+func ParseSeverity(s string) (Severity, bool) {
+	switch s {
+	case "error", "":
+		return Error, true
+	case "warn", "warning":
+		return Warning, true
+	case "info":
+		return Info, true
+	}
+	return Error, false
+}
+
+// Diagnostic is a single finding, ready to be rendered by any Reporter
+// without further access to whatever produced it (an *ast.Node, a
+// parsed markdown file, ...).
+type Diagnostic struct {
+	File          string
+	Line          int
+	Column        int
+	EndLine       int
+	EndColumn     int
+	RuleID        string
+	Severity      Severity
+	Message       string
+	ResourceName  string
+	AttributePath string
+}
@@ -0,0 +1,130 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RuleDescriber resolves a rule ID to its human-readable description,
+// used to populate the SARIF rule metadata. Each checker already knows
+// how to describe its own rule IDs, so callers pass a closure rather
+// than this package importing either one.
+type RuleDescriber func(ruleID string) string
+
+// Reporter renders a batch of Diagnostics. Implementations are chosen
+// by the -format flag both binaries expose.
+type Reporter interface {
+	Report(w io.Writer, diagnostics []Diagnostic) error
+}
+
+// ReporterFor returns the Reporter for one of the supported -format
+// values: text (default), json, sarif, checkstyle, github-actions.
+// describe may be nil, in which case rule descriptions are omitted.
+func ReporterFor(format string, describe RuleDescriber) (Reporter, error) {
+	if describe == nil {
+		describe = func(string) string { return "" }
+	}
+	switch format {
+	case "", "text":
+		return textReporter{}, nil
+	case "json":
+		return jsonReporter{}, nil
+	case "sarif":
+		return sarifReporter{describe: describe}, nil
+	case "checkstyle":
+		return checkstyleReporter{}, nil
+	case "github-actions":
+		return githubActionsReporter{}, nil
+	}
+	return nil, fmt.Errorf("unknown -format %q", format)
+}
+
+type textReporter struct{}
+
+func (textReporter) Report(w io.Writer, diagnostics []Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d [%s/%s] %s\n", d.File, d.Line, d.Column, d.RuleID, d.Severity, d.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonReporter struct{}
+
+func (jsonReporter) Report(w io.Writer, diagnostics []Diagnostic) error {
+	type jsonDiagnostic struct {
+		File          string `json:"file"`
+		Line          int    `json:"line"`
+		Column        int    `json:"column,omitempty"`
+		EndLine       int    `json:"endLine,omitempty"`
+		EndColumn     int    `json:"endColumn,omitempty"`
+		RuleID        string `json:"ruleId"`
+		Severity      string `json:"severity"`
+		Message       string `json:"message"`
+		ResourceName  string `json:"resourceName,omitempty"`
+		AttributePath string `json:"attributePath,omitempty"`
+	}
+	out := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		out = append(out, jsonDiagnostic{
+			File:          d.File,
+			Line:          d.Line,
+			Column:        d.Column,
+			EndLine:       d.EndLine,
+			EndColumn:     d.EndColumn,
+			RuleID:        d.RuleID,
+			Severity:      d.Severity.String(),
+			Message:       d.Message,
+			ResourceName:  d.ResourceName,
+			AttributePath: d.AttributePath,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// githubActionsReporter emits workflow commands
+// (::error file=...,line=...::message) that GitHub Actions turns into
+// inline PR annotations.
+type githubActionsReporter struct{}
+
+func (githubActionsReporter) Report(w io.Writer, diagnostics []Diagnostic) error {
+	for _, d := range diagnostics {
+		level := "error"
+		switch d.Severity {
+		case Warning:
+			level = "warning"
+		case Info:
+			level = "notice"
+		}
+		if _, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d::[%s] %s\n", level, d.File, d.Line, d.Column, d.RuleID, d.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Info:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+func checkstyleSeverity(s Severity) string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Info:
+		return "info"
+	default:
+		return "error"
+	}
+}
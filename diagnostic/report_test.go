@@ -0,0 +1,112 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleDiagnostics() []Diagnostic {
+	return []Diagnostic{
+		{
+			File: "resource_widget.go", Line: 12, Column: 3, EndLine: 12, EndColumn: 20,
+			RuleID: "description", Severity: Error, Message: "name: Missing Description attribute",
+			ResourceName: "widget_thing", AttributePath: "name",
+		},
+		{
+			File: "resource_widget.go", Line: 20, Column: 3,
+			RuleID: "sensitive-non-string", Severity: Warning, Message: "count: Sensitive set on a non-string attribute",
+			ResourceName: "widget_thing", AttributePath: "count",
+		},
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (textReporter{}).Report(&buf, sampleDiagnostics()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "resource_widget.go:12:3 [description/error] name: Missing Description attribute") {
+		t.Fatalf("unexpected text output:\n%s", out)
+	}
+	if !strings.Contains(out, "[sensitive-non-string/warn]") {
+		t.Fatalf("expected warn severity rendered, got:\n%s", out)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (jsonReporter{}).Report(&buf, sampleDiagnostics()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	var out []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(out))
+	}
+	if out[0]["ruleId"] != "description" || out[0]["severity"] != "error" {
+		t.Fatalf("unexpected first entry: %v", out[0])
+	}
+}
+
+func TestGithubActionsReporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (githubActionsReporter{}).Report(&buf, sampleDiagnostics()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "::error file=resource_widget.go,line=12,col=3::[description]") {
+		t.Fatalf("unexpected error annotation:\n%s", out)
+	}
+	if !strings.Contains(out, "::warning file=resource_widget.go,line=20,col=3::[sensitive-non-string]") {
+		t.Fatalf("unexpected warning annotation:\n%s", out)
+	}
+}
+
+func TestCheckstyleReporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (checkstyleReporter{}).Report(&buf, sampleDiagnostics()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `<file name="resource_widget.go">`) {
+		t.Fatalf("expected a single grouped <file> element:\n%s", out)
+	}
+	if !strings.Contains(out, `severity="warning"`) {
+		t.Fatalf("expected Warning to render as checkstyle severity %q:\n%s", "warning", out)
+	}
+}
+
+func TestSarifReporter(t *testing.T) {
+	var buf bytes.Buffer
+	describe := func(ruleID string) string { return "desc:" + ruleID }
+	if err := (sarifReporter{describe: describe}).Report(&buf, sampleDiagnostics()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	var out sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("output isn't valid SARIF JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.Runs) != 1 || len(out.Runs[0].Results) != 2 {
+		t.Fatalf("expected a single run with 2 results, got %+v", out)
+	}
+	rules := out.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "description" || rules[0].ShortDescription.Text != "desc:description" {
+		t.Fatalf("expected deduped rule metadata with descriptions, got %+v", rules)
+	}
+}
+
+func TestReporterFor(t *testing.T) {
+	for _, format := range []string{"", "text", "json", "sarif", "checkstyle", "github-actions"} {
+		if _, err := ReporterFor(format, nil); err != nil {
+			t.Errorf("ReporterFor(%q): %v", format, err)
+		}
+	}
+	if _, err := ReporterFor("xml", nil); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
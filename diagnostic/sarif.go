@@ -0,0 +1,114 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// sarifReporter renders SARIF v2.1, grouped by rule, so results can be
+// uploaded to GitHub code scanning.
+type sarifReporter struct {
+	describe RuleDescriber
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string `json:"id"`
+	ShortDescription struct {
+		Text string `json:"text"`
+	} `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func (r sarifReporter) Report(w io.Writer, diagnostics []Diagnostic) error {
+	seenRules := map[string]bool{}
+	rules := []sarifRule{}
+	results := make([]sarifResult, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		if !seenRules[d.RuleID] {
+			seenRules[d.RuleID] = true
+			rule := sarifRule{ID: d.RuleID}
+			rule.ShortDescription.Text = r.describe(d.RuleID)
+			rules = append(rules, rule)
+		}
+		results = append(results, sarifResult{
+			RuleID:  d.RuleID,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: d.File},
+					Region: sarifRegion{
+						StartLine:   d.Line,
+						StartColumn: d.Column,
+						EndLine:     d.EndLine,
+						EndColumn:   d.EndColumn,
+					},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "terraform-tools", Rules: rules}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}